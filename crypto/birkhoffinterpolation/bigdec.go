@@ -0,0 +1,142 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import "math/big"
+
+// BigDec is a fixed-precision decimal: it represents unscaled /
+// 2^fractionalBits. Unlike RatElement it never grows unboundedly, at the
+// cost of needing to round on every Mul/Inv; rounding that would discard
+// more than Tolerance of the true value fails with ErrPrecisionLoss instead
+// of silently losing accuracy.
+type BigDec struct {
+	unscaled       *big.Int
+	fractionalBits uint
+	tolerance      *big.Rat
+}
+
+// NewBigDec builds a BigDec approximating r, keeping fractionalBits bits
+// after the binary point and failing any later operation whose rounding
+// error would exceed tolerance (a fraction of the operation's true result;
+// e.g. big.NewRat(1, 1000000) allows up to one part in a million of error).
+// A nil tolerance means no operation is ever allowed to round at all.
+func NewBigDec(r *big.Rat, fractionalBits uint, tolerance *big.Rat) *BigDec {
+	return &BigDec{
+		unscaled:       roundRatToScale(r, fractionalBits),
+		fractionalBits: fractionalBits,
+		tolerance:      tolerance,
+	}
+}
+
+// Rat returns the exact rational value this BigDec currently holds.
+func (d *BigDec) Rat() *big.Rat {
+	scale := new(big.Int).Lsh(big.NewInt(1), d.fractionalBits)
+	return new(big.Rat).SetFrac(d.unscaled, scale)
+}
+
+func roundRatToScale(r *big.Rat, fractionalBits uint) *big.Int {
+	scale := new(big.Int).Lsh(big.NewInt(1), fractionalBits)
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(scale))
+	return roundRat(scaled)
+}
+
+// roundRat rounds a rational number to the nearest integer (ties away from
+// zero).
+func roundRat(r *big.Rat) *big.Int {
+	num := r.Num()
+	den := r.Denom()
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	doubledRemainder := new(big.Int).Lsh(new(big.Int).Abs(remainder), 1)
+	if doubledRemainder.CmpAbs(den) >= 0 {
+		if num.Sign() >= 0 {
+			quotient.Add(quotient, big.NewInt(1))
+		} else {
+			quotient.Sub(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
+
+func (d *BigDec) withinTolerance(exact, rounded *big.Rat) bool {
+	if d.tolerance == nil {
+		return exact.Cmp(rounded) == 0
+	}
+	diff := new(big.Rat).Sub(exact, rounded)
+	diff.Abs(diff)
+	if exact.Sign() == 0 {
+		return diff.Sign() == 0
+	}
+	relative := new(big.Rat).Quo(diff, new(big.Rat).Abs(exact))
+	return relative.Cmp(d.tolerance) <= 0
+}
+
+func (d *BigDec) round(exact *big.Rat) (*BigDec, error) {
+	unscaled := roundRatToScale(exact, d.fractionalBits)
+	result := &BigDec{unscaled: unscaled, fractionalBits: d.fractionalBits, tolerance: d.tolerance}
+	if !d.withinTolerance(exact, result.Rat()) {
+		return nil, ErrPrecisionLoss
+	}
+	return result, nil
+}
+
+func (d *BigDec) Add(other Field) Field {
+	o := other.(*BigDec)
+	return &BigDec{
+		unscaled:       new(big.Int).Add(d.unscaled, o.unscaled),
+		fractionalBits: d.fractionalBits,
+		tolerance:      d.tolerance,
+	}
+}
+
+func (d *BigDec) Neg() Field {
+	return &BigDec{
+		unscaled:       new(big.Int).Neg(d.unscaled),
+		fractionalBits: d.fractionalBits,
+		tolerance:      d.tolerance,
+	}
+}
+
+// Mul multiplies two BigDecs, rounding the exact product back down to
+// fractionalBits. It returns ErrPrecisionLoss if that rounding exceeds
+// tolerance.
+func (d *BigDec) Mul(other Field) (Field, error) {
+	o := other.(*BigDec)
+	exact := new(big.Rat).Mul(d.Rat(), o.Rat())
+	result, err := d.round(exact)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (d *BigDec) Inv() (Field, error) {
+	if d.unscaled.Sign() == 0 {
+		return nil, matrixZeroInverseErr
+	}
+	exact := new(big.Rat).Inv(d.Rat())
+	return d.round(exact)
+}
+
+func (d *BigDec) IsZero() bool {
+	return d.unscaled.Sign() == 0
+}
+
+func (d *BigDec) FromInt64(n int64) Field {
+	return &BigDec{
+		unscaled:       new(big.Int).Lsh(big.NewInt(n), d.fractionalBits),
+		fractionalBits: d.fractionalBits,
+		tolerance:      d.tolerance,
+	}
+}
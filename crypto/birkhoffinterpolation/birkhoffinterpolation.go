@@ -0,0 +1,301 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package birkhoffinterpolation implements Birkhoff interpolation over a
+// finite field: given a set of (x, rank) pairs and the corresponding values
+// of the rank-th derivative of an unknown polynomial at x, it computes the
+// coefficients needed to reconstruct the polynomial's value at 0 (i.e. the
+// shared secret).
+package birkhoffinterpolation
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/getamis/alice/crypto/matrix"
+	"github.com/getamis/alice/crypto/utils"
+)
+
+var (
+	// ErrInvalidBks is returned when the given BkParameters contain
+	// duplicate x coordinates, or do not carry enough distinct rank
+	// information to satisfy the given threshold.
+	ErrInvalidBks = errors.New("invalid bks")
+	// ErrNoValidBks is returned when the given BkParameters cannot recover
+	// a secret at the given threshold under any circumstance (e.g. none of
+	// them hold a rank-0 share).
+	ErrNoValidBks = errors.New("no valid bks")
+	// ErrEqualOrLargerThreshold is returned when there are not enough
+	// BkParameters to satisfy the requested threshold.
+	ErrEqualOrLargerThreshold = errors.New("threshold is equal or larger than the number of bks")
+)
+
+// BkParameter is a single Birkhoff interpolation parameter: the x coordinate
+// of a party, together with the order (rank) of the derivative share it
+// holds.
+type BkParameter struct {
+	x     *big.Int
+	rank  uint32
+	curve Curve
+}
+
+// NewBkParameter builds a BkParameter for x holding a rank-th derivative
+// share.
+func NewBkParameter(x *big.Int, rank uint32) *BkParameter {
+	return &BkParameter{
+		x:    new(big.Int).Set(x),
+		rank: rank,
+	}
+}
+
+// GetX returns the x coordinate.
+func (p *BkParameter) GetX() *big.Int {
+	return p.x
+}
+
+// GetRank returns the derivative order this parameter represents.
+func (p *BkParameter) GetRank() uint32 {
+	return p.rank
+}
+
+func (p *BkParameter) String() string {
+	return fmt.Sprintf("(x, rank) = (%s, %d)", p.x.String(), p.rank)
+}
+
+// BkParameters is the set of BkParameter held by the parties participating
+// in a Birkhoff-interpolation-based secret sharing scheme.
+type BkParameters []*BkParameter
+
+// CheckValid checks that ps can actually reconstruct a secret at the given
+// threshold. The Pólya condition (the i-th smallest rank, 0-indexed, must not
+// exceed i) is necessary but not sufficient: it only bounds what *could* be
+// recoverable, not whether the resulting Birkhoff matrix for a given quorum is
+// invertible. A quorum of parties whose ranks satisfy Pólya can still yield a
+// singular matrix (e.g. (1,0),(2,1),(3,0) at threshold 3: sorted ranks 0,0,1
+// pass Pólya, but the matrix has determinant 0). So CheckValid enumerates
+// every threshold-sized quorum drawn from parties whose rank is below
+// threshold (a party ranked at or beyond threshold can never contribute to
+// any quorum) and checks the matrix of every quorum that passes Pólya on its
+// own. If none of them even passes Pólya, ps is hopeless and CheckValid
+// returns ErrNoValidBks; if at least one does but any such quorum turns out
+// singular, it returns ErrInvalidBks, since a real signing quorum could land
+// on exactly that combination and fail.
+//
+// This is exponential in the number of parties below threshold; that's fine
+// for the small committees Birkhoff-based threshold schemes are built for,
+// but CheckValid is not meant to be called on every signing attempt.
+func (ps BkParameters) CheckValid(threshold uint32, fieldOrder *big.Int) error {
+	if err := utils.EnsureFieldOrder(fieldOrder); err != nil {
+		return err
+	}
+	if uint32(len(ps)) <= threshold {
+		return ErrEqualOrLargerThreshold
+	}
+
+	seenX := make(map[string]struct{}, len(ps))
+	for _, p := range ps {
+		key := p.x.String()
+		if _, ok := seenX[key]; ok {
+			return ErrInvalidBks
+		}
+		seenX[key] = struct{}{}
+	}
+
+	fullMatrix, err := ps.getLinearEquationCoefficientMatrix(threshold, fieldOrder)
+	if err != nil {
+		return err
+	}
+
+	pool := make([]int, 0, len(ps))
+	for i, p := range ps {
+		if p.rank < threshold {
+			pool = append(pool, i)
+		}
+	}
+	if uint32(len(pool)) < threshold {
+		return ErrNoValidBks
+	}
+
+	sawCandidate := false
+	combo := make([]int, threshold)
+	var visit func(start, depth int) error
+	visit = func(start, depth int) error {
+		if uint32(depth) == threshold {
+			ranks := make([]uint32, threshold)
+			for i, idx := range combo {
+				ranks[i] = ps[idx].rank
+			}
+			sort.Slice(ranks, func(i, j int) bool { return ranks[i] < ranks[j] })
+			for i, r := range ranks {
+				if r > uint32(i) {
+					// This quorum fails Pólya on its own; it was never a
+					// viable combination, so it doesn't count against ps.
+					return nil
+				}
+			}
+			sawCandidate = true
+
+			rows := make([][]*big.Int, threshold)
+			for i, idx := range combo {
+				rows[i] = fullMatrix.GetRow(idx)
+			}
+			sub, err := matrix.NewMatrix(fieldOrder, rows)
+			if err != nil {
+				return err
+			}
+			if _, err := sub.Inverse(); err != nil {
+				return ErrInvalidBks
+			}
+			return nil
+		}
+		for i := start; i < len(pool); i++ {
+			combo[depth] = pool[i]
+			if err := visit(i+1, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(0, 0); err != nil {
+		return err
+	}
+	if !sawCandidate {
+		return ErrNoValidBks
+	}
+	return nil
+}
+
+// buildBkRows builds, for every element of ps, the row of coefficients that
+// map a degree-(threshold-1) polynomial's coefficients to that element's
+// rank-th derivative at its x, lifting x (and every intermediate value) into
+// the Field produced by toField. This is the one place the permutation-
+// coefficient/power math lives: getLinearEquationCoefficientMatrix
+// instantiates it with zpElement, and ComputeBkCoefficientRational/
+// ComputeBkCoefficientFixedPoint in rational.go instantiate it with
+// RatElement/BigDec, so a fix to this math never has to be made twice.
+func buildBkRows(ps BkParameters, threshold uint32, toField func(*big.Int) Field) ([][]Field, error) {
+	zero := toField(big.NewInt(0))
+	rows := make([][]Field, len(ps))
+	for i, p := range ps {
+		x := toField(p.x)
+		row := make([]Field, threshold)
+		for c := uint32(0); c < threshold; c++ {
+			if c < p.rank {
+				row[c] = zero.FromInt64(0)
+				continue
+			}
+			// permutationCoefficient = c! / (c - rank)!
+			permutationCoefficient := zero.FromInt64(1)
+			for k := uint32(0); k < p.rank; k++ {
+				var err error
+				permutationCoefficient, err = permutationCoefficient.Mul(zero.FromInt64(int64(c - k)))
+				if err != nil {
+					return nil, err
+				}
+			}
+			power := zero.FromInt64(1)
+			for e := uint32(0); e < c-p.rank; e++ {
+				var err error
+				power, err = power.Mul(x)
+				if err != nil {
+					return nil, err
+				}
+			}
+			var err error
+			row[c], err = permutationCoefficient.Mul(power)
+			if err != nil {
+				return nil, err
+			}
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// getLinearEquationCoefficientMatrix builds the matrix M such that, for a
+// degree-(threshold-1) polynomial f, M * (a_0, ..., a_{threshold-1})^T gives
+// the rank-th derivative of f evaluated at x, one row per element of ps.
+func (ps BkParameters) getLinearEquationCoefficientMatrix(threshold uint32, fieldOrder *big.Int) (*matrix.Matrix, error) {
+	if err := utils.EnsureFieldOrder(fieldOrder); err != nil {
+		return nil, err
+	}
+
+	rows, err := buildBkRows(ps, threshold, func(x *big.Int) Field { return newZpElement(x, fieldOrder) })
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]*big.Int, len(rows))
+	for i, row := range rows {
+		result[i] = make([]*big.Int, len(row))
+		for j, f := range row {
+			result[i][j] = f.(*zpElement).v
+		}
+	}
+	return matrix.NewMatrix(fieldOrder, result)
+}
+
+// ComputeBkCoefficient computes, for every element of ps, the coefficient
+// c_i such that the secret (the constant term of the underlying polynomial)
+// equals sum_i c_i * share_i, using exactly threshold of the ps to build an
+// invertible system. Elements of ps that are not part of the chosen system
+// get a coefficient of 0. This is the Zp instantiation of
+// computeBkCoefficientOverField, via zpElement.
+func (ps BkParameters) ComputeBkCoefficient(threshold uint32, fieldOrder *big.Int) ([]*big.Int, error) {
+	if err := utils.EnsureFieldOrder(fieldOrder); err != nil {
+		return nil, err
+	}
+
+	fields, err := ps.computeBkCoefficientOverField(threshold, func(x *big.Int) Field {
+		return newZpElement(x, fieldOrder)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*big.Int, len(fields))
+	for i, f := range fields {
+		result[i] = f.(*zpElement).v
+	}
+	return result, nil
+}
+
+// selectSquareSubsystem picks threshold indices of ps, one per derivative
+// order 0..threshold-1, by choosing the lowest-rank party that can still
+// cover each required order.
+func selectSquareSubsystem(ps BkParameters, threshold uint32) ([]int, error) {
+	type candidate struct {
+		index int
+		rank  uint32
+	}
+	candidates := make([]candidate, len(ps))
+	for i, p := range ps {
+		candidates[i] = candidate{index: i, rank: p.rank}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].rank < candidates[j].rank })
+
+	selected := make([]int, 0, threshold)
+	for _, c := range candidates {
+		if uint32(len(selected)) >= threshold {
+			break
+		}
+		if c.rank <= uint32(len(selected)) {
+			selected = append(selected, c.index)
+		}
+	}
+	if uint32(len(selected)) < threshold {
+		return nil, matrix.ErrNotInvertableMatrix
+	}
+	return selected, nil
+}
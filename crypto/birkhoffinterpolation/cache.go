@@ -0,0 +1,172 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// DefaultBkCoefficientCacheCapacity is used by NewBkCoefficientCache when a
+// caller doesn't have a more specific bound in mind.
+const DefaultBkCoefficientCacheCapacity = 256
+
+// bkCoefficientCacheKey canonically identifies a (BkParameters, threshold,
+// fieldOrder) triple: ps is sorted by x before hashing so that the same
+// participant set in a different slice order hits the same entry.
+type bkCoefficientCacheKey [sha256.Size]byte
+
+func newBkCoefficientCacheKey(ps BkParameters, threshold uint32, fieldOrder *big.Int) bkCoefficientCacheKey {
+	sorted := make(BkParameters, len(ps))
+	copy(sorted, ps)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].x.Cmp(sorted[j].x) < 0 })
+
+	h := sha256.New()
+	var lengthPrefix [4]byte
+	binary.BigEndian.PutUint32(lengthPrefix[:], threshold)
+	h.Write(lengthPrefix[:])
+	h.Write(fieldOrder.Bytes())
+	for _, p := range sorted {
+		encoded, _ := p.MarshalBinary()
+		binary.BigEndian.PutUint32(lengthPrefix[:], uint32(len(encoded)))
+		h.Write(lengthPrefix[:])
+		h.Write(encoded)
+	}
+
+	var key bkCoefficientCacheKey
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// BkCoefficientCache memoizes BkParameters.ComputeBkCoefficient results,
+// keyed by the canonical hash of (sorted BkParameters, threshold,
+// fieldOrder), with LRU eviction once Capacity entries are held. It is safe
+// for concurrent use. The zero value is not usable; use
+// NewBkCoefficientCache.
+type BkCoefficientCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[bkCoefficientCacheKey]*list.Element
+	order    *list.List // most-recently-used at the front
+}
+
+type bkCoefficientCacheEntry struct {
+	key          bkCoefficientCacheKey
+	coefficients []*big.Int
+}
+
+// NewBkCoefficientCache creates a cache holding at most capacity entries.
+// capacity <= 0 falls back to DefaultBkCoefficientCacheCapacity.
+func NewBkCoefficientCache(capacity int) *BkCoefficientCache {
+	if capacity <= 0 {
+		capacity = DefaultBkCoefficientCacheCapacity
+	}
+	return &BkCoefficientCache{
+		capacity: capacity,
+		entries:  make(map[bkCoefficientCacheKey]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// cloneBigInts returns a deep copy of in: a fresh slice holding fresh
+// *big.Int values. The cache must never hand out, or retain, a *big.Int it
+// doesn't own - big.Int's usual in-place methods (Add, Mod, ...) would
+// otherwise let one caller's mutation corrupt every other holder of the
+// same cache entry, including concurrent ones.
+func cloneBigInts(in []*big.Int) []*big.Int {
+	out := make([]*big.Int, len(in))
+	for i, v := range in {
+		out[i] = new(big.Int).Set(v)
+	}
+	return out
+}
+
+// Get returns a deep copy of the cached coefficients for (ps, threshold,
+// fieldOrder), and whether they were found. A cache hit is promoted to
+// most-recently-used.
+func (c *BkCoefficientCache) Get(ps BkParameters, threshold uint32, fieldOrder *big.Int) ([]*big.Int, bool) {
+	key := newBkCoefficientCacheKey(ps, threshold, fieldOrder)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return cloneBigInts(elem.Value.(*bkCoefficientCacheEntry).coefficients), true
+}
+
+// Put stores a deep copy of coefficients for (ps, threshold, fieldOrder),
+// evicting the least-recently-used entry if the cache is at capacity.
+// Copying on the way in means the caller is free to mutate the slice (or
+// its *big.Int elements) it passed in afterwards without corrupting the
+// cached entry.
+func (c *BkCoefficientCache) Put(ps BkParameters, threshold uint32, fieldOrder *big.Int, coefficients []*big.Int) {
+	key := newBkCoefficientCacheKey(ps, threshold, fieldOrder)
+	stored := cloneBigInts(coefficients)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*bkCoefficientCacheEntry).coefficients = stored
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&bkCoefficientCacheEntry{key: key, coefficients: stored})
+	c.entries[key] = elem
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*bkCoefficientCacheEntry).key)
+		}
+	}
+}
+
+// Invalidate removes any cached entry for (ps, threshold, fieldOrder).
+func (c *BkCoefficientCache) Invalidate(ps BkParameters, threshold uint32, fieldOrder *big.Int) {
+	key := newBkCoefficientCacheKey(ps, threshold, fieldOrder)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	c.order.Remove(elem)
+	delete(c.entries, key)
+}
+
+// ComputeBkCoefficient is a cached wrapper around
+// BkParameters.ComputeBkCoefficient: it returns the memoized result for
+// (ps, threshold, fieldOrder) if present, otherwise computes, caches and
+// returns it. Errors are not cached.
+func (c *BkCoefficientCache) ComputeBkCoefficient(ps BkParameters, threshold uint32, fieldOrder *big.Int) ([]*big.Int, error) {
+	if cached, ok := c.Get(ps, threshold, fieldOrder); ok {
+		return cached, nil
+	}
+	coefficients, err := ps.ComputeBkCoefficient(threshold, fieldOrder)
+	if err != nil {
+		return nil, err
+	}
+	c.Put(ps, threshold, fieldOrder, coefficients)
+	return coefficients, nil
+}
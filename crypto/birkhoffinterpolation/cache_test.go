@@ -0,0 +1,167 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BkCoefficientCache", func() {
+	var (
+		bigNumber   = "115792089237316195423570985008687907852837564279074904382605163141518161494337"
+		bigPrime, _ = new(big.Int).SetString(bigNumber, 10)
+	)
+
+	newPs := func() BkParameters {
+		return BkParameters{
+			NewBkParameter(big.NewInt(1), 0),
+			NewBkParameter(big.NewInt(2), 1),
+			NewBkParameter(big.NewInt(3), 2),
+			NewBkParameter(big.NewInt(4), 3),
+		}
+	}
+
+	It("misses then hits for the same key", func() {
+		cache := NewBkCoefficientCache(DefaultBkCoefficientCacheCapacity)
+		ps := newPs()
+
+		_, ok := cache.Get(ps, 3, bigPrime)
+		Expect(ok).Should(BeFalse())
+
+		expected, err := ps.ComputeBkCoefficient(3, bigPrime)
+		Expect(err).Should(BeNil())
+		cache.Put(ps, 3, bigPrime, expected)
+
+		got, ok := cache.Get(ps, 3, bigPrime)
+		Expect(ok).Should(BeTrue())
+		Expect(got).Should(Equal(expected))
+	})
+
+	It("is insensitive to the input slice's order", func() {
+		cache := NewBkCoefficientCache(DefaultBkCoefficientCacheCapacity)
+		ps := newPs()
+		expected, err := ps.ComputeBkCoefficient(3, bigPrime)
+		Expect(err).Should(BeNil())
+		cache.Put(ps, 3, bigPrime, expected)
+
+		reordered := BkParameters{ps[2], ps[0], ps[3], ps[1]}
+		_, ok := cache.Get(reordered, 3, bigPrime)
+		Expect(ok).Should(BeTrue())
+	})
+
+	It("evicts the least-recently-used entry once over capacity", func() {
+		cache := NewBkCoefficientCache(1)
+		ps1 := newPs()
+		ps2 := BkParameters{
+			NewBkParameter(big.NewInt(10), 0),
+			NewBkParameter(big.NewInt(20), 1),
+			NewBkParameter(big.NewInt(30), 2),
+			NewBkParameter(big.NewInt(40), 3),
+		}
+
+		cache.Put(ps1, 3, bigPrime, []*big.Int{big.NewInt(1)})
+		cache.Put(ps2, 3, bigPrime, []*big.Int{big.NewInt(2)})
+
+		_, ok := cache.Get(ps1, 3, bigPrime)
+		Expect(ok).Should(BeFalse())
+		_, ok = cache.Get(ps2, 3, bigPrime)
+		Expect(ok).Should(BeTrue())
+	})
+
+	It("Invalidate removes an entry", func() {
+		cache := NewBkCoefficientCache(DefaultBkCoefficientCacheCapacity)
+		ps := newPs()
+		cache.Put(ps, 3, bigPrime, []*big.Int{big.NewInt(1)})
+		cache.Invalidate(ps, 3, bigPrime)
+
+		_, ok := cache.Get(ps, 3, bigPrime)
+		Expect(ok).Should(BeFalse())
+	})
+
+	It("is not corrupted by the caller mutating a returned slice's big.Ints in place", func() {
+		cache := NewBkCoefficientCache(DefaultBkCoefficientCacheCapacity)
+		ps := newPs()
+
+		got, err := cache.ComputeBkCoefficient(ps, 3, bigPrime)
+		Expect(err).Should(BeNil())
+		expected := new(big.Int).Set(got[0])
+
+		got[0].Add(got[0], big.NewInt(1000))
+
+		again, err := cache.ComputeBkCoefficient(ps, 3, bigPrime)
+		Expect(err).Should(BeNil())
+		Expect(again[0]).Should(Equal(expected))
+	})
+
+	It("is safe for concurrent use", func() {
+		cache := NewBkCoefficientCache(DefaultBkCoefficientCacheCapacity)
+		ps := newPs()
+		var wg sync.WaitGroup
+		for i := 0; i < 32; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = cache.ComputeBkCoefficient(ps, 3, bigPrime)
+			}()
+		}
+		wg.Wait()
+
+		got, ok := cache.Get(ps, 3, bigPrime)
+		Expect(ok).Should(BeTrue())
+		Expect(got).ShouldNot(BeNil())
+	})
+})
+
+func BenchmarkComputeBkCoefficient_Uncached(b *testing.B) {
+	bigPrime, _ := new(big.Int).SetString("115792089237316195423570985008687907852837564279074904382605163141518161494337", 10)
+	ps := BkParameters{
+		NewBkParameter(big.NewInt(1), 0),
+		NewBkParameter(big.NewInt(2), 1),
+		NewBkParameter(big.NewInt(3), 2),
+		NewBkParameter(big.NewInt(4), 3),
+		NewBkParameter(big.NewInt(5), 4),
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ps.ComputeBkCoefficient(4, bigPrime); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkComputeBkCoefficient_Cached(b *testing.B) {
+	bigPrime, _ := new(big.Int).SetString("115792089237316195423570985008687907852837564279074904382605163141518161494337", 10)
+	ps := BkParameters{
+		NewBkParameter(big.NewInt(1), 0),
+		NewBkParameter(big.NewInt(2), 1),
+		NewBkParameter(big.NewInt(3), 2),
+		NewBkParameter(big.NewInt(4), 3),
+		NewBkParameter(big.NewInt(5), 4),
+	}
+	cache := NewBkCoefficientCache(DefaultBkCoefficientCacheCapacity)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := cache.ComputeBkCoefficient(ps, 4, bigPrime); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrUnsupportedCurve is returned when a Curve value has no known scalar
+// field order.
+var ErrUnsupportedCurve = errors.New("unsupported curve")
+
+// Curve identifies the scalar field a BkParameter's x coordinate (and the
+// shares interpolated alongside it) lives in. The zero value,
+// CurveSECP256K1, keeps NewBkParameter's existing behaviour so callers that
+// only ever dealt with ECDSA-style curves don't need to change.
+type Curve int
+
+const (
+	// CurveSECP256K1 is the scalar field of secp256k1 (and, generically, any
+	// prime-order curve whose order is supplied directly as fieldOrder).
+	CurveSECP256K1 Curve = iota
+	// CurveBLS12381G1 is the r-order scalar field shared by the G1 subgroup
+	// of BLS12-381.
+	CurveBLS12381G1
+	// CurveBLS12381G2 is the r-order scalar field shared by the G2 subgroup
+	// of BLS12-381. It is numerically identical to CurveBLS12381G1's, since
+	// G1 and G2 share the same scalar field order r, but is kept distinct so
+	// BkParameters can record which group a share was produced for.
+	CurveBLS12381G2
+)
+
+// bls12381ScalarFieldOrder is the order r of the BLS12-381 G1/G2 subgroups.
+var bls12381ScalarFieldOrder, _ = new(big.Int).SetString(
+	"52435875175126190479447740508185965837690552500527637822603658699938581184513", 10,
+)
+
+// FieldOrder returns the scalar field order associated with c.
+func (c Curve) FieldOrder() (*big.Int, error) {
+	switch c {
+	case CurveSECP256K1:
+		secp256k1Order, _ := new(big.Int).SetString(
+			"115792089237316195423570985008687907852837564279074904382605163141518161494337", 10,
+		)
+		return secp256k1Order, nil
+	case CurveBLS12381G1, CurveBLS12381G2:
+		return new(big.Int).Set(bls12381ScalarFieldOrder), nil
+	default:
+		return nil, ErrUnsupportedCurve
+	}
+}
+
+func (c Curve) String() string {
+	switch c {
+	case CurveSECP256K1:
+		return "secp256k1"
+	case CurveBLS12381G1:
+		return "bls12-381-g1"
+	case CurveBLS12381G2:
+		return "bls12-381-g2"
+	default:
+		return "unknown"
+	}
+}
+
+// NewBkParameterWithCurve builds a BkParameter the same way NewBkParameter
+// does, additionally recording which curve's scalar field x and the
+// resulting shares belong to.
+func NewBkParameterWithCurve(x *big.Int, rank uint32, curve Curve) *BkParameter {
+	bk := NewBkParameter(x, rank)
+	bk.curve = curve
+	return bk
+}
+
+// GetCurve returns the curve this BkParameter was created for.
+func (p *BkParameter) GetCurve() Curve {
+	return p.curve
+}
+
+// ComputeBkCoefficientForCurve is a convenience wrapper around
+// ComputeBkCoefficient that looks up the field order for curve instead of
+// requiring the caller to supply it, for ps that were all built with
+// NewBkParameterWithCurve(_, _, curve).
+func (ps BkParameters) ComputeBkCoefficientForCurve(threshold uint32, curve Curve) ([]*big.Int, error) {
+	fieldOrder, err := curve.FieldOrder()
+	if err != nil {
+		return nil, err
+	}
+	return ps.ComputeBkCoefficient(threshold, fieldOrder)
+}
@@ -0,0 +1,144 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrPrecisionLoss is returned by fixed-point Field operations (BigDec) when
+// rounding the exact result to the configured number of fractional bits
+// would exceed the caller's tolerance.
+var ErrPrecisionLoss = errors.New("operation would lose more precision than tolerated")
+
+// Field is the arithmetic a Birkhoff coefficient computation needs from its
+// scalar domain. buildBkRows and invertFieldMatrix are written against this
+// interface (rather than directly against *big.Int mod a prime) so the same
+// row-construction and Gauss-Jordan inversion logic can run over Zp
+// (zpElement, which ComputeBkCoefficient and getLinearEquationCoefficientMatrix
+// instantiate it with), over Q (RatElement), or over a fixed-precision
+// decimal (BigDec) for non-cryptographic use cases such as reconstructing
+// weighted quantities where reducing modulo a prime makes no sense.
+type Field interface {
+	// Add and Neg never lose precision: for a fixed-precision
+	// implementation sharing one scale, addition and negation are exact.
+	Add(other Field) Field
+	Neg() Field
+	// Mul and Inv can round (for a fixed-precision implementation) and so
+	// return ErrPrecisionLoss if doing so loses more precision than the
+	// implementation's configured tolerance. RatElement's Mul/Inv never
+	// error (except inverting zero).
+	Mul(other Field) (Field, error)
+	Inv() (Field, error)
+	IsZero() bool
+	// FromInt64 builds a new element of the same field/precision as the
+	// receiver, representing n. It's used internally to build constants
+	// (0, 1, powers of x, ...) without the caller having to know the
+	// concrete Field implementation in use.
+	FromInt64(n int64) Field
+}
+
+// RatElement is a Field implementation backed by *big.Rat, i.e. exact
+// arithmetic over Q.
+type RatElement struct {
+	v *big.Rat
+}
+
+// NewRatElement wraps r as a Field element.
+func NewRatElement(r *big.Rat) *RatElement {
+	return &RatElement{v: new(big.Rat).Set(r)}
+}
+
+// Rat returns the underlying rational value.
+func (e *RatElement) Rat() *big.Rat {
+	return new(big.Rat).Set(e.v)
+}
+
+func (e *RatElement) Add(other Field) Field {
+	o := other.(*RatElement)
+	return &RatElement{v: new(big.Rat).Add(e.v, o.v)}
+}
+
+func (e *RatElement) Neg() Field {
+	return &RatElement{v: new(big.Rat).Neg(e.v)}
+}
+
+func (e *RatElement) Mul(other Field) (Field, error) {
+	o := other.(*RatElement)
+	return &RatElement{v: new(big.Rat).Mul(e.v, o.v)}, nil
+}
+
+func (e *RatElement) Inv() (Field, error) {
+	if e.v.Sign() == 0 {
+		return nil, matrixZeroInverseErr
+	}
+	return &RatElement{v: new(big.Rat).Inv(e.v)}, nil
+}
+
+func (e *RatElement) IsZero() bool {
+	return e.v.Sign() == 0
+}
+
+func (e *RatElement) FromInt64(n int64) Field {
+	return &RatElement{v: new(big.Rat).SetInt64(n)}
+}
+
+var matrixZeroInverseErr = errors.New("cannot invert zero field element")
+
+// zpElement is the Field implementation backed by *big.Int reduced modulo
+// fieldOrder, i.e. Z/fieldOrderZ. It's what lets
+// getLinearEquationCoefficientMatrix and ComputeBkCoefficient share
+// buildBkRows/invertFieldMatrix with the Q/BigDec paths in rational.go,
+// instead of keeping a second *big.Int-only copy of that math.
+type zpElement struct {
+	v          *big.Int
+	fieldOrder *big.Int
+}
+
+// newZpElement reduces v modulo fieldOrder and wraps it as a Field element.
+func newZpElement(v, fieldOrder *big.Int) *zpElement {
+	return &zpElement{v: new(big.Int).Mod(v, fieldOrder), fieldOrder: fieldOrder}
+}
+
+func (e *zpElement) Add(other Field) Field {
+	o := other.(*zpElement)
+	return newZpElement(new(big.Int).Add(e.v, o.v), e.fieldOrder)
+}
+
+func (e *zpElement) Neg() Field {
+	return newZpElement(new(big.Int).Neg(e.v), e.fieldOrder)
+}
+
+func (e *zpElement) Mul(other Field) (Field, error) {
+	o := other.(*zpElement)
+	return newZpElement(new(big.Int).Mul(e.v, o.v), e.fieldOrder), nil
+}
+
+func (e *zpElement) Inv() (Field, error) {
+	inv := new(big.Int).ModInverse(e.v, e.fieldOrder)
+	if inv == nil {
+		return nil, matrixZeroInverseErr
+	}
+	return &zpElement{v: inv, fieldOrder: e.fieldOrder}, nil
+}
+
+func (e *zpElement) IsZero() bool {
+	return e.v.Sign() == 0
+}
+
+func (e *zpElement) FromInt64(n int64) Field {
+	return newZpElement(big.NewInt(n), e.fieldOrder)
+}
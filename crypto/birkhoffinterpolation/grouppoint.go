@@ -0,0 +1,78 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrMismatchedShares is returned when the number of BkParameters does not
+// match the number of group-element shares supplied for reconstruction.
+var ErrMismatchedShares = errors.New("number of bks does not match number of shares")
+
+// Point is the minimal interface an additively-homomorphic group element
+// (e.g. a pairing-friendly curve point) must satisfy for
+// ReconstructThresholdGroupShare to combine shares of it. A pairing library's
+// G1/G2 point type is the motivating implementation - a BLS partial signature
+// is exactly such a share - but Point makes no assumption beyond the group
+// being additive and having a well-defined scalar multiplication, so it's
+// equally usable for any other linear, Birkhoff-reconstructible scheme built
+// over the same scalar field as Curve.FieldOrder.
+//
+// This package does not implement or depend on a real pairing-friendly curve
+// (there is no such library vendored here), so nothing in this repo verifies
+// that a concrete G1/G2 implementation's Add/ScalarMult actually reconstructs
+// a valid BLS signature end-to-end (i.e. one that passes pairing-based
+// verification against the aggregate public key). That verification is the
+// caller's Point implementation's responsibility; what this file guarantees
+// is only the linear-combination arithmetic below.
+type Point interface {
+	Add(q Point) Point
+	ScalarMult(scalar *big.Int) Point
+}
+
+// ReconstructThresholdGroupShare combines per-party shares of a group element
+// into the combined element, the same way ComputeBkCoefficient reconstructs a
+// scalar secret, except the linear combination runs over Point instead of
+// *big.Int: result = sum_i c_i * shares_i. bks and shares must be parallel
+// slices; shares[i] is the group-element share held by the party described by
+// bks[i]. Used with BLS partial signatures as shares, this reconstructs the
+// full signature from any threshold-sized quorum's partial signatures,
+// relying on the additive homomorphism BLS signatures are built on.
+func ReconstructThresholdGroupShare(bks BkParameters, shares []Point, threshold uint32, curve Curve) (Point, error) {
+	if len(bks) != len(shares) {
+		return nil, ErrMismatchedShares
+	}
+
+	coefficients, err := bks.ComputeBkCoefficientForCurve(threshold, curve)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Point
+	for i, c := range coefficients {
+		if c.Sign() == 0 {
+			continue
+		}
+		term := shares[i].ScalarMult(c)
+		if result == nil {
+			result = term
+			continue
+		}
+		result = result.Add(term)
+	}
+	return result, nil
+}
@@ -0,0 +1,84 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"math/big"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// toyPoint is a stand-in additive group (Z/fieldOrderZ, + and *), not a
+// pairing-friendly curve point. It exercises the linear-combination
+// arithmetic in ReconstructThresholdGroupShare - that the right coefficients
+// get applied to the right shares - but proves nothing about real curve
+// arithmetic or BLS signature verification, since there is no pairing
+// library in this repo to test against. Plugging a real G1/G2 implementation
+// in as Point is what would make this a threshold BLS reconstruction in
+// practice.
+type toyPoint struct {
+	scalar     *big.Int
+	fieldOrder *big.Int
+}
+
+func (p *toyPoint) Add(q Point) Point {
+	other := q.(*toyPoint)
+	sum := new(big.Int).Add(p.scalar, other.scalar)
+	sum.Mod(sum, p.fieldOrder)
+	return &toyPoint{scalar: sum, fieldOrder: p.fieldOrder}
+}
+
+func (p *toyPoint) ScalarMult(scalar *big.Int) Point {
+	product := new(big.Int).Mul(p.scalar, scalar)
+	product.Mod(product, p.fieldOrder)
+	return &toyPoint{scalar: product, fieldOrder: p.fieldOrder}
+}
+
+var _ = Describe("ReconstructThresholdGroupShare()", func() {
+	It("reconstructs a shared group element from Birkhoff shares of it", func() {
+		fieldOrder, err := CurveBLS12381G1.FieldOrder()
+		Expect(err).Should(BeNil())
+
+		// f(z) = secret + 7*z, secret is the "signature scalar" we're
+		// reconstructing; shares are plain evaluations (rank 0).
+		secret := big.NewInt(42)
+		slope := big.NewInt(7)
+		f := func(x int64) *big.Int {
+			v := new(big.Int).Mul(slope, big.NewInt(x))
+			v.Add(v, secret)
+			return v.Mod(v, fieldOrder)
+		}
+
+		bks := make(BkParameters, 3)
+		shares := make([]Point, 3)
+		for i, x := range []int64{1, 2, 3} {
+			bks[i] = NewBkParameterWithCurve(big.NewInt(x), 0, CurveBLS12381G1)
+			shares[i] = &toyPoint{scalar: f(x), fieldOrder: fieldOrder}
+		}
+
+		got, err := ReconstructThresholdGroupShare(bks, shares, 2, CurveBLS12381G1)
+		Expect(err).Should(BeNil())
+		Expect(got.(*toyPoint).scalar).Should(Equal(secret))
+	})
+
+	It("rejects mismatched shares", func() {
+		bks := make(BkParameters, 2)
+		bks[0] = NewBkParameterWithCurve(big.NewInt(1), 0, CurveBLS12381G1)
+		bks[1] = NewBkParameterWithCurve(big.NewInt(2), 1, CurveBLS12381G1)
+		_, err := ReconstructThresholdGroupShare(bks, []Point{&toyPoint{}}, 2, CurveBLS12381G1)
+		Expect(err).Should(Equal(ErrMismatchedShares))
+	})
+})
@@ -0,0 +1,170 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrNotInvertibleField is the Field-generic analogue of
+// matrix.ErrNotInvertableMatrix: the selected system has no inverse over
+// the field in use.
+var ErrNotInvertibleField = errors.New("not invertible over field")
+
+// ComputeBkCoefficientRational is the non-modular counterpart of
+// ComputeBkCoefficient: it reconstructs the Birkhoff coefficients exactly
+// over Q instead of reducing modulo a prime field order, for callers
+// applying Birkhoff interpolation to ordinary (non-cryptographic) weighted
+// quantities.
+func (ps BkParameters) ComputeBkCoefficientRational(threshold uint32) ([]*big.Rat, error) {
+	fields, err := ps.computeBkCoefficientOverField(threshold, func(x *big.Int) Field {
+		return NewRatElement(new(big.Rat).SetInt(x))
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*big.Rat, len(fields))
+	for i, f := range fields {
+		result[i] = f.(*RatElement).Rat()
+	}
+	return result, nil
+}
+
+// ComputeBkCoefficientFixedPoint is the fixed-precision counterpart of
+// ComputeBkCoefficientRational: coefficients are rounded to fractionalBits
+// bits after the binary point at every intermediate step, returning
+// ErrPrecisionLoss instead of silently accumulating rounding error beyond
+// tolerance (see BigDec for the semantics of tolerance).
+func (ps BkParameters) ComputeBkCoefficientFixedPoint(threshold uint32, fractionalBits uint, tolerance *big.Rat) ([]*BigDec, error) {
+	fields, err := ps.computeBkCoefficientOverField(threshold, func(x *big.Int) Field {
+		return NewBigDec(new(big.Rat).SetInt(x), fractionalBits, tolerance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*BigDec, len(fields))
+	for i, f := range fields {
+		result[i] = f.(*BigDec)
+	}
+	return result, nil
+}
+
+// computeBkCoefficientOverField is shared by ComputeBkCoefficient,
+// ComputeBkCoefficientRational, and ComputeBkCoefficientFixedPoint: it uses
+// buildBkRows with toField to lift the selected threshold x threshold
+// system into the target Field, inverts it, and scatters the resulting
+// coefficients back to len(ps).
+func (ps BkParameters) computeBkCoefficientOverField(threshold uint32, toField func(*big.Int) Field) ([]Field, error) {
+	if uint32(len(ps)) <= threshold {
+		return nil, ErrEqualOrLargerThreshold
+	}
+	selected, err := selectSquareSubsystem(ps, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	selectedPs := make(BkParameters, len(selected))
+	for i, idx := range selected {
+		selectedPs[i] = ps[idx]
+	}
+	rows, err := buildBkRows(selectedPs, threshold, toField)
+	if err != nil {
+		return nil, err
+	}
+
+	zero := toField(big.NewInt(0))
+	inverse, err := invertFieldMatrix(rows, zero)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Field, len(ps))
+	for i := range result {
+		result[i] = zero.FromInt64(0)
+	}
+	for i, idx := range selected {
+		result[idx] = inverse[0][i]
+	}
+	return result, nil
+}
+
+// invertFieldMatrix inverts the square matrix rows via Gauss-Jordan
+// elimination over Field, returning ErrNotInvertibleField if no pivot can
+// be found and propagating any error a Field operation itself returns
+// (e.g. BigDec's ErrPrecisionLoss).
+func invertFieldMatrix(rows [][]Field, zero Field) ([][]Field, error) {
+	n := len(rows)
+	aug := make([][]Field, n)
+	for i := 0; i < n; i++ {
+		if len(rows[i]) != n {
+			return nil, ErrNotInvertibleField
+		}
+		aug[i] = make([]Field, 2*n)
+		copy(aug[i], rows[i])
+		for j := 0; j < n; j++ {
+			if i == j {
+				aug[i][n+j] = zero.FromInt64(1)
+			} else {
+				aug[i][n+j] = zero.FromInt64(0)
+			}
+		}
+	}
+
+	for col := 0; col < n; col++ {
+		pivotRow := -1
+		for row := col; row < n; row++ {
+			if !aug[row][col].IsZero() {
+				pivotRow = row
+				break
+			}
+		}
+		if pivotRow == -1 {
+			return nil, ErrNotInvertibleField
+		}
+		aug[col], aug[pivotRow] = aug[pivotRow], aug[col]
+
+		inv, err := aug[col][col].Inv()
+		if err != nil {
+			return nil, err
+		}
+		for j := 0; j < 2*n; j++ {
+			aug[col][j], err = aug[col][j].Mul(inv)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for row := 0; row < n; row++ {
+			if row == col || aug[row][col].IsZero() {
+				continue
+			}
+			factor := aug[row][col]
+			for j := 0; j < 2*n; j++ {
+				product, err := factor.Mul(aug[col][j])
+				if err != nil {
+					return nil, err
+				}
+				aug[row][j] = aug[row][j].Add(product.Neg())
+			}
+		}
+	}
+
+	inverse := make([][]Field, n)
+	for i := 0; i < n; i++ {
+		inverse[i] = aug[i][n:]
+	}
+	return inverse, nil
+}
@@ -0,0 +1,85 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"math/big"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ComputeBkCoefficientRational()", func() {
+	It("reconstructs a weighted quantity without reducing modulo a prime", func() {
+		// f(z) = 10 + 3z; secret (f(0)) is 10, reconstructed from two
+		// rank-0 evaluations.
+		ps := BkParameters{
+			NewBkParameter(big.NewInt(1), 0),
+			NewBkParameter(big.NewInt(2), 0),
+			NewBkParameter(big.NewInt(3), 0),
+		}
+		got, err := ps.ComputeBkCoefficientRational(2)
+		Expect(err).Should(BeNil())
+
+		shares := []*big.Rat{big.NewRat(13, 1), big.NewRat(16, 1), big.NewRat(19, 1)}
+		secret := new(big.Rat)
+		for i, c := range got {
+			secret.Add(secret, new(big.Rat).Mul(c, shares[i]))
+		}
+		Expect(secret).Should(Equal(big.NewRat(10, 1)))
+	})
+
+	It("leaves a party whose rank is at or beyond the threshold with a zero coefficient", func() {
+		ps := BkParameters{
+			NewBkParameter(big.NewInt(1), 0),
+			NewBkParameter(big.NewInt(2), 1),
+			NewBkParameter(big.NewInt(3), 2),
+			NewBkParameter(big.NewInt(4), 3),
+		}
+		got, err := ps.ComputeBkCoefficientRational(3)
+		Expect(err).Should(BeNil())
+		Expect(got[0]).Should(Equal(big.NewRat(1, 1)))
+		Expect(got[1]).Should(Equal(big.NewRat(-1, 1)))
+		Expect(got[2]).Should(Equal(big.NewRat(3, 2)))
+		Expect(got[3]).Should(Equal(big.NewRat(0, 1)))
+	})
+})
+
+var _ = Describe("ComputeBkCoefficientFixedPoint()", func() {
+	It("reconstructs within the configured precision", func() {
+		ps := BkParameters{
+			NewBkParameter(big.NewInt(1), 0),
+			NewBkParameter(big.NewInt(2), 1),
+			NewBkParameter(big.NewInt(3), 2),
+			NewBkParameter(big.NewInt(4), 3),
+		}
+		got, err := ps.ComputeBkCoefficientFixedPoint(3, 60, big.NewRat(1, 1000000))
+		Expect(err).Should(BeNil())
+		Expect(got[0].Rat()).Should(Equal(big.NewRat(1, 1)))
+		Expect(got[2].Rat()).Should(Equal(big.NewRat(3, 2)))
+	})
+
+	It("returns ErrPrecisionLoss when rounding would exceed a zero tolerance", func() {
+		ps := BkParameters{
+			NewBkParameter(big.NewInt(1), 0),
+			NewBkParameter(big.NewInt(4), 0),
+			NewBkParameter(big.NewInt(2), 0),
+		}
+		// The resulting coefficient is -1/3, which has no exact
+		// fixed-point representation, forcing rounding.
+		_, err := ps.ComputeBkCoefficientFixedPoint(2, 4, big.NewRat(0, 1))
+		Expect(err).Should(Equal(ErrPrecisionLoss))
+	})
+})
@@ -0,0 +1,107 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/getamis/alice/crypto/matrix"
+	"github.com/getamis/alice/crypto/utils"
+)
+
+// ErrIncreasingThresholdNotSupported is returned by ReshareCoefficients
+// when newThreshold > oldThreshold: raising the threshold means the new
+// committee needs shares of a higher-degree polynomial, which requires
+// fresh, jointly-generated randomness the old parties don't have access to
+// individually. ReshareCoefficients only derives a purely linear transform
+// of the existing shares, so it can reshare to an equal or lower threshold
+// (rotating/shrinking the committee while keeping the same secret and
+// polynomial degree) but not grow it.
+var ErrIncreasingThresholdNotSupported = errors.New("resharing to a larger threshold is not supported")
+
+// ReshareCoefficients computes the matrix M (len(newPs) x len(ps)) such
+// that, given the old parties' shares s_1..s_n (s_i is the rank_i-th
+// derivative of the shared polynomial f at ps[i].x), each new party j can
+// derive its own share s'_j = sum_i M[j][i] * s_i, without any old party
+// ever combining shares to recover f(0) itself.
+//
+// f has degree oldThreshold-1, so simply re-evaluating f (or a derivative of
+// it) at the new parties' points would still require oldThreshold of the new
+// shares to reconstruct - the threshold wouldn't actually have changed. To
+// genuinely lower it, the new committee instead shares h, the degree-
+// (newThreshold-1) truncation of f's Taylor expansion around 0 (i.e. f's
+// first newThreshold coefficients, a_0..a_{newThreshold-1}, dropping the
+// rest). h(0) = a_0 = f(0), so the secret is preserved, and h has exactly the
+// degree a newThreshold-sized quorum needs.
+//
+// Each a_k is itself a linear combination of the old shares: inverting the
+// old oldThreshold x oldThreshold system gives every Taylor coefficient of f,
+// not just a_0 (ComputeBkCoefficient only ever uses row 0 of that inverse for
+// the secret itself). Row j of M is therefore newRow_j * oldSystem^-1, but
+// with newRow_j built against the newThreshold-sized (not oldThreshold-
+// sized) basis, so it only ever reaches into a_0..a_{newThreshold-1}.
+func (ps BkParameters) ReshareCoefficients(newPs BkParameters, oldThreshold, newThreshold uint32, fieldOrder *big.Int) ([][]*big.Int, error) {
+	if err := utils.EnsureFieldOrder(fieldOrder); err != nil {
+		return nil, err
+	}
+	if newThreshold > oldThreshold {
+		return nil, ErrIncreasingThresholdNotSupported
+	}
+
+	oldFullMatrix, err := ps.getLinearEquationCoefficientMatrix(oldThreshold, fieldOrder)
+	if err != nil {
+		return nil, err
+	}
+	oldSelected, err := selectSquareSubsystem(ps, oldThreshold)
+	if err != nil {
+		return nil, err
+	}
+	oldRows := make([][]*big.Int, len(oldSelected))
+	for i, idx := range oldSelected {
+		oldRows[i] = oldFullMatrix.GetRow(idx)
+	}
+	oldSystem, err := matrix.NewMatrix(fieldOrder, oldRows)
+	if err != nil {
+		return nil, err
+	}
+	oldInverse, err := oldSystem.Inverse()
+	if err != nil {
+		return nil, err
+	}
+
+	newFullMatrix, err := newPs.getLinearEquationCoefficientMatrix(newThreshold, fieldOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]*big.Int, len(newPs))
+	for j := range newPs {
+		newRow := newFullMatrix.GetRow(j)
+		row := make([]*big.Int, len(ps))
+		for i := range row {
+			row[i] = big.NewInt(0)
+		}
+		for i, idx := range oldSelected {
+			term := big.NewInt(0)
+			for k, coefficient := range newRow {
+				term = utils.Mod(term.Add(term, new(big.Int).Mul(coefficient, oldInverse.Get(k, i))), fieldOrder)
+			}
+			row[idx] = term
+		}
+		result[j] = row
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"math/big"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReshareCoefficients()", func() {
+	var (
+		bigNumber   = "115792089237316195423570985008687907852837564279074904382605163141518161494337"
+		bigPrime, _ = new(big.Int).SetString(bigNumber, 10)
+	)
+
+	// f(z) = 5 + 3z + 2z^2; f'(z) = 3 + 4z; f''(z) = 4.
+	f := func(x int64) *big.Int {
+		v := big.NewInt(2 * x * x)
+		v.Add(v, big.NewInt(3*x))
+		v.Add(v, big.NewInt(5))
+		return v.Mod(v, bigPrime)
+	}
+	fPrime := func(x int64) *big.Int {
+		v := big.NewInt(4 * x)
+		v.Add(v, big.NewInt(3))
+		return v.Mod(v, bigPrime)
+	}
+
+	reconstructNewShare := func(m []*big.Int, oldShares []*big.Int) *big.Int {
+		sum := big.NewInt(0)
+		for i, coefficient := range m {
+			sum.Add(sum, new(big.Int).Mul(coefficient, oldShares[i]))
+		}
+		return sum.Mod(sum, bigPrime)
+	}
+
+	It("reshares a (1,0),(2,1),(3,2) committee to a fresh same-threshold committee", func() {
+		ps := BkParameters{
+			NewBkParameter(big.NewInt(1), 0),
+			NewBkParameter(big.NewInt(2), 1),
+			NewBkParameter(big.NewInt(3), 2),
+		}
+		oldShares := []*big.Int{f(1), fPrime(2), big.NewInt(4)} // f''(x) == 4 everywhere
+
+		newPs := BkParameters{
+			NewBkParameter(big.NewInt(4), 0),
+			NewBkParameter(big.NewInt(5), 0),
+			NewBkParameter(big.NewInt(6), 1),
+		}
+
+		m, err := ps.ReshareCoefficients(newPs, 3, 3, bigPrime)
+		Expect(err).Should(BeNil())
+		Expect(len(m)).Should(Equal(3))
+
+		Expect(reconstructNewShare(m[0], oldShares)).Should(Equal(f(4)))
+		Expect(reconstructNewShare(m[1], oldShares)).Should(Equal(f(5)))
+		Expect(reconstructNewShare(m[2], oldShares)).Should(Equal(fPrime(6)))
+	})
+
+	It("reshares a committee to a new, lower-threshold committee that can still recover the secret", func() {
+		ps := BkParameters{
+			NewBkParameter(big.NewInt(1), 0),
+			NewBkParameter(big.NewInt(2), 1),
+			NewBkParameter(big.NewInt(3), 2),
+		}
+		oldShares := []*big.Int{f(1), fPrime(2), big.NewInt(4)}
+
+		// Party at x=1 stays on the committee; x=2,3 are replaced by x=9,10.
+		// A third new party (x=10) gives the new committee the redundancy
+		// ComputeBkCoefficient needs to select a threshold-sized quorum.
+		newPs := BkParameters{
+			NewBkParameter(big.NewInt(1), 0),
+			NewBkParameter(big.NewInt(9), 0),
+			NewBkParameter(big.NewInt(10), 0),
+		}
+
+		m, err := ps.ReshareCoefficients(newPs, 3, 2, bigPrime)
+		Expect(err).Should(BeNil())
+
+		newShares := []*big.Int{
+			reconstructNewShare(m[0], oldShares),
+			reconstructNewShare(m[1], oldShares),
+			reconstructNewShare(m[2], oldShares),
+		}
+
+		// The new shares must come from a genuine degree-1 polynomial, not
+		// from f itself (which has degree 2): any newThreshold of them
+		// recover the original secret, f(0).
+		coefficients, err := newPs.ComputeBkCoefficient(2, bigPrime)
+		Expect(err).Should(BeNil())
+		secret := big.NewInt(0)
+		for i, c := range coefficients {
+			secret.Add(secret, new(big.Int).Mul(c, newShares[i]))
+		}
+		secret.Mod(secret, bigPrime)
+		Expect(secret).Should(Equal(f(0)))
+	})
+
+	It("rejects raising the threshold", func() {
+		ps := BkParameters{
+			NewBkParameter(big.NewInt(1), 0),
+			NewBkParameter(big.NewInt(2), 1),
+			NewBkParameter(big.NewInt(3), 2),
+		}
+		newPs := BkParameters{
+			NewBkParameter(big.NewInt(4), 0),
+			NewBkParameter(big.NewInt(5), 1),
+			NewBkParameter(big.NewInt(6), 2),
+			NewBkParameter(big.NewInt(7), 3),
+		}
+		_, err := ps.ReshareCoefficients(newPs, 3, 4, bigPrime)
+		Expect(err).Should(Equal(ErrIncreasingThresholdNotSupported))
+	})
+})
@@ -0,0 +1,476 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// ErrInvalidWireFormat is returned when Unmarshal/UnmarshalBinary/
+// UnmarshalCompressed is given data that is truncated or otherwise doesn't
+// match the expected layout.
+var ErrInvalidWireFormat = errors.New("invalid wire format")
+
+// curveTag encodes Curve as the single byte that, analogous to the 0x02/
+// 0x03/0x04 prefix on an EC public key, precedes a BkParameter's coordinate
+// bytes on the wire so a reader in any language can recover which scalar
+// field x belongs to without extra context.
+func curveTag(c Curve) byte {
+	return byte(c)
+}
+
+func curveFromTag(tag byte) Curve {
+	return Curve(tag)
+}
+
+// MarshalBinary encodes p as: 1-byte curve tag, 4-byte big-endian rank,
+// 2-byte big-endian length of x's bytes, then x's big-endian bytes.
+func (p *BkParameter) MarshalBinary() ([]byte, error) {
+	xBytes := p.x.Bytes()
+	if len(xBytes) > 0xFFFF {
+		return nil, ErrInvalidWireFormat
+	}
+	buf := make([]byte, 1+4+2+len(xBytes))
+	buf[0] = curveTag(p.curve)
+	binary.BigEndian.PutUint32(buf[1:5], p.rank)
+	binary.BigEndian.PutUint16(buf[5:7], uint16(len(xBytes)))
+	copy(buf[7:], xBytes)
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a buffer produced by MarshalBinary into p.
+func (p *BkParameter) UnmarshalBinary(data []byte) error {
+	consumed, err := p.unmarshalBinaryAt(data)
+	if err != nil {
+		return err
+	}
+	if consumed != len(data) {
+		return ErrInvalidWireFormat
+	}
+	return nil
+}
+
+// unmarshalBinaryAt decodes a single BkParameter from the start of data and
+// returns how many bytes it consumed, so callers (e.g. BkParameters) can
+// decode a concatenation of these without a further length prefix per item.
+func (p *BkParameter) unmarshalBinaryAt(data []byte) (int, error) {
+	if len(data) < 7 {
+		return 0, ErrInvalidWireFormat
+	}
+	curve := curveFromTag(data[0])
+	rank := binary.BigEndian.Uint32(data[1:5])
+	xLen := int(binary.BigEndian.Uint16(data[5:7]))
+	if len(data) < 7+xLen {
+		return 0, ErrInvalidWireFormat
+	}
+	p.x = new(big.Int).SetBytes(data[7 : 7+xLen])
+	p.rank = rank
+	p.curve = curve
+	return 7 + xLen, nil
+}
+
+// Marshal encodes p as an actual protobuf wire-format message matching
+// BkParameterMessage in bk.proto (bytes x = 1; uint32 rank = 2; uint32
+// curve = 3), hand-written rather than protoc-generated since this repo has
+// no protobuf toolchain available. Unlike MarshalBinary's fixed layout, this
+// is real protobuf: a non-Go node can decode it with any protobuf library
+// given bk.proto, which MarshalBinary's bespoke format can't offer.
+// Proto3 default values (rank 0, curve 0, empty x) are omitted, matching
+// what a generated Marshal would produce.
+func (p *BkParameter) Marshal() ([]byte, error) {
+	var buf []byte
+	if xBytes := p.x.Bytes(); len(xBytes) > 0 {
+		buf = appendProtoTag(buf, 1, protoWireBytes)
+		buf = appendProtoVarint(buf, uint64(len(xBytes)))
+		buf = append(buf, xBytes...)
+	}
+	if p.rank != 0 {
+		buf = appendProtoTag(buf, 2, protoWireVarint)
+		buf = appendProtoVarint(buf, uint64(p.rank))
+	}
+	if p.curve != 0 {
+		buf = appendProtoTag(buf, 3, protoWireVarint)
+		buf = appendProtoVarint(buf, uint64(p.curve))
+	}
+	return buf, nil
+}
+
+// Unmarshal decodes a buffer produced by Marshal (or any protobuf encoder
+// using bk.proto's BkParameterMessage) into p. Per protobuf's
+// forward-compatibility contract, a field number this decoder doesn't
+// recognize (e.g. one a newer writer added to bk.proto) is skipped using its
+// wire type rather than rejected, so bk.proto can grow new fields without
+// breaking older readers.
+func (p *BkParameter) Unmarshal(data []byte) error {
+	x := big.NewInt(0)
+	var rank uint32
+	var curve Curve
+	for len(data) > 0 {
+		fieldNum, wireType, n, err := decodeProtoTag(data)
+		if err != nil {
+			return err
+		}
+		data = data[n:]
+		switch {
+		case fieldNum == 1 && wireType == protoWireBytes:
+			length, n, err := decodeProtoVarint(data)
+			if err != nil || length > uint64(len(data)-n) {
+				return ErrInvalidWireFormat
+			}
+			data = data[n:]
+			x = new(big.Int).SetBytes(data[:int(length)])
+			data = data[int(length):]
+		case fieldNum == 2 && wireType == protoWireVarint:
+			v, n, err := decodeProtoVarint(data)
+			if err != nil {
+				return err
+			}
+			rank = uint32(v)
+			data = data[n:]
+		case fieldNum == 3 && wireType == protoWireVarint:
+			v, n, err := decodeProtoVarint(data)
+			if err != nil {
+				return err
+			}
+			curve = Curve(v)
+			data = data[n:]
+		default:
+			skipped, err := skipProtoField(data, wireType)
+			if err != nil {
+				return err
+			}
+			data = data[skipped:]
+		}
+	}
+	p.x = x
+	p.rank = rank
+	p.curve = curve
+	return nil
+}
+
+// MarshalBinary encodes ps as a 4-byte big-endian count followed by the
+// concatenation of each element's MarshalBinary encoding.
+func (ps BkParameters) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, uint32(len(ps)))
+	for _, p := range ps {
+		encoded, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, encoded...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a buffer produced by BkParameters.MarshalBinary
+// into *ps.
+func (ps *BkParameters) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return ErrInvalidWireFormat
+	}
+	count := binary.BigEndian.Uint32(data[:4])
+	data = data[4:]
+	result := make(BkParameters, count)
+	for i := uint32(0); i < count; i++ {
+		p := &BkParameter{}
+		consumed, err := p.unmarshalBinaryAt(data)
+		if err != nil {
+			return err
+		}
+		result[i] = p
+		data = data[consumed:]
+	}
+	if len(data) != 0 {
+		return ErrInvalidWireFormat
+	}
+	*ps = result
+	return nil
+}
+
+// Marshal encodes ps as an actual protobuf wire-format message matching
+// BkParametersMessage in bk.proto (repeated BkParameterMessage items = 1):
+// each element is written as its own length-delimited field 1, exactly as a
+// generated Marshal for a repeated embedded message would.
+func (ps BkParameters) Marshal() ([]byte, error) {
+	var buf []byte
+	for _, p := range ps {
+		item, err := p.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		buf = appendProtoTag(buf, 1, protoWireBytes)
+		buf = appendProtoVarint(buf, uint64(len(item)))
+		buf = append(buf, item...)
+	}
+	return buf, nil
+}
+
+// Unmarshal decodes a buffer produced by Marshal (or any protobuf encoder
+// using bk.proto's BkParametersMessage) into *ps. As in BkParameter.Unmarshal,
+// a field number other than 1 (items) is skipped rather than rejected, so a
+// field a newer writer added to BkParametersMessage doesn't break this
+// reader.
+func (ps *BkParameters) Unmarshal(data []byte) error {
+	result := make(BkParameters, 0)
+	for len(data) > 0 {
+		fieldNum, wireType, n, err := decodeProtoTag(data)
+		if err != nil {
+			return err
+		}
+		data = data[n:]
+		if fieldNum != 1 || wireType != protoWireBytes {
+			skipped, err := skipProtoField(data, wireType)
+			if err != nil {
+				return err
+			}
+			data = data[skipped:]
+			continue
+		}
+		length, n, err := decodeProtoVarint(data)
+		if err != nil || length > uint64(len(data)-n) {
+			return ErrInvalidWireFormat
+		}
+		data = data[n:]
+		p := &BkParameter{}
+		if err := p.Unmarshal(data[:int(length)]); err != nil {
+			return err
+		}
+		result = append(result, p)
+		data = data[int(length):]
+	}
+	*ps = result
+	return nil
+}
+
+// MarshalCompressed encodes ps in a compact form for an already-sorted
+// BkParameters: a single 1-byte curve tag (all elements must share a
+// curve), a 4-byte count, and then, per element, only the 4-byte rank and
+// length-prefixed x bytes, omitting the now-redundant per-element curve tag.
+func (ps BkParameters) MarshalCompressed() ([]byte, error) {
+	if len(ps) == 0 {
+		return nil, ErrInvalidWireFormat
+	}
+	curve := ps[0].curve
+	buf := make([]byte, 1+4)
+	buf[0] = curveTag(curve)
+	binary.BigEndian.PutUint32(buf[1:5], uint32(len(ps)))
+	for _, p := range ps {
+		if p.curve != curve {
+			return nil, ErrInvalidWireFormat
+		}
+		xBytes := p.x.Bytes()
+		if len(xBytes) > 0xFFFF {
+			return nil, ErrInvalidWireFormat
+		}
+		entry := make([]byte, 4+2+len(xBytes))
+		binary.BigEndian.PutUint32(entry[:4], p.rank)
+		binary.BigEndian.PutUint16(entry[4:6], uint16(len(xBytes)))
+		copy(entry[6:], xBytes)
+		buf = append(buf, entry...)
+	}
+	return buf, nil
+}
+
+// UnmarshalCompressed decodes a buffer produced by MarshalCompressed.
+func (ps *BkParameters) UnmarshalCompressed(data []byte) error {
+	if len(data) < 5 {
+		return ErrInvalidWireFormat
+	}
+	curve := curveFromTag(data[0])
+	count := binary.BigEndian.Uint32(data[1:5])
+	data = data[5:]
+
+	result := make(BkParameters, count)
+	for i := uint32(0); i < count; i++ {
+		if len(data) < 6 {
+			return ErrInvalidWireFormat
+		}
+		rank := binary.BigEndian.Uint32(data[:4])
+		xLen := int(binary.BigEndian.Uint16(data[4:6]))
+		if len(data) < 6+xLen {
+			return ErrInvalidWireFormat
+		}
+		result[i] = &BkParameter{
+			x:     new(big.Int).SetBytes(data[6 : 6+xLen]),
+			rank:  rank,
+			curve: curve,
+		}
+		data = data[6+xLen:]
+	}
+	if len(data) != 0 {
+		return ErrInvalidWireFormat
+	}
+	*ps = result
+	return nil
+}
+
+// Protobuf wire types, as defined by the protobuf encoding spec. This package
+// only ever encodes varint and length-delimited fields, but a message may
+// still contain fixed32/fixed64 fields written by some other implementation
+// of bk.proto, so skipProtoField needs to recognize them too.
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+)
+
+// skipProtoField skips an unknown field's value of the given wire type at
+// the start of data and returns how many bytes it consumed. Protobuf's
+// forward-compatibility contract requires this: a reader that doesn't
+// recognize a field number must use its wire type to skip the value, not
+// reject the whole message, so that a future writer can add fields to
+// bk.proto without breaking older readers.
+func skipProtoField(data []byte, wireType int) (int, error) {
+	switch wireType {
+	case protoWireVarint:
+		_, n, err := decodeProtoVarint(data)
+		if err != nil {
+			return 0, err
+		}
+		return n, nil
+	case protoWireBytes:
+		length, n, err := decodeProtoVarint(data)
+		if err != nil || length > uint64(len(data)-n) {
+			return 0, ErrInvalidWireFormat
+		}
+		return n + int(length), nil
+	case protoWireFixed64:
+		if len(data) < 8 {
+			return 0, ErrInvalidWireFormat
+		}
+		return 8, nil
+	case protoWireFixed32:
+		if len(data) < 4 {
+			return 0, ErrInvalidWireFormat
+		}
+		return 4, nil
+	default:
+		return 0, ErrInvalidWireFormat
+	}
+}
+
+// appendProtoTag appends a protobuf field tag: (fieldNum << 3) | wireType,
+// varint-encoded.
+func appendProtoTag(buf []byte, fieldNum int, wireType int) []byte {
+	return appendProtoVarint(buf, uint64(fieldNum)<<3|uint64(wireType))
+}
+
+// appendProtoVarint appends v as a protobuf-style unsigned LEB128 varint.
+func appendProtoVarint(buf []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
+
+// decodeProtoVarint decodes a varint from the start of data, returning its
+// value and how many bytes it consumed.
+func decodeProtoVarint(data []byte) (uint64, int, error) {
+	var v uint64
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		v |= uint64(b&0x7F) << uint(7*i)
+		if b&0x80 == 0 {
+			return v, i + 1, nil
+		}
+	}
+	return 0, 0, ErrInvalidWireFormat
+}
+
+// decodeProtoTag decodes a field tag from the start of data into its field
+// number and wire type, returning how many bytes it consumed.
+func decodeProtoTag(data []byte) (fieldNum int, wireType int, consumed int, err error) {
+	tag, n, err := decodeProtoVarint(data)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return int(tag >> 3), int(tag & 0x7), n, nil
+}
+
+// bkParameterJSON is the canonical JSON representation of a BkParameter: x
+// is hex-encoded big-endian bytes so it round-trips unambiguously across
+// languages, rather than relying on JSON-number precision.
+type bkParameterJSON struct {
+	X     string `json:"x"`
+	Rank  uint32 `json:"rank"`
+	Curve Curve  `json:"curve"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p *BkParameter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bkParameterJSON{
+		X:     hex.EncodeToString(p.x.Bytes()),
+		Rank:  p.rank,
+		Curve: p.curve,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *BkParameter) UnmarshalJSON(data []byte) error {
+	var raw bkParameterJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	xBytes, err := hex.DecodeString(raw.X)
+	if err != nil {
+		return err
+	}
+	p.x = new(big.Int).SetBytes(xBytes)
+	p.rank = raw.Rank
+	p.curve = raw.Curve
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for the whole set.
+func (ps BkParameters) MarshalJSON() ([]byte, error) {
+	raw := make([]bkParameterJSON, len(ps))
+	for i, p := range ps {
+		raw[i] = bkParameterJSON{
+			X:     hex.EncodeToString(p.x.Bytes()),
+			Rank:  p.rank,
+			Curve: p.curve,
+		}
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON implements json.Unmarshaler for the whole set.
+func (ps *BkParameters) UnmarshalJSON(data []byte) error {
+	var raw []bkParameterJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	result := make(BkParameters, len(raw))
+	for i, r := range raw {
+		xBytes, err := hex.DecodeString(r.X)
+		if err != nil {
+			return err
+		}
+		result[i] = &BkParameter{
+			x:     new(big.Int).SetBytes(xBytes),
+			rank:  r.Rank,
+			curve: r.Curve,
+		}
+	}
+	*ps = result
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package birkhoffinterpolation
+
+import (
+	"math/big"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Serialization", func() {
+	newSample := func() BkParameters {
+		return BkParameters{
+			NewBkParameterWithCurve(big.NewInt(1), 0, CurveBLS12381G1),
+			NewBkParameterWithCurve(big.NewInt(2), 1, CurveBLS12381G1),
+			NewBkParameterWithCurve(big.NewInt(300), 2, CurveBLS12381G1),
+		}
+	}
+
+	It("round-trips a single BkParameter through MarshalBinary/UnmarshalBinary", func() {
+		p := NewBkParameterWithCurve(big.NewInt(12345), 3, CurveBLS12381G2)
+		encoded, err := p.MarshalBinary()
+		Expect(err).Should(BeNil())
+
+		got := &BkParameter{}
+		Expect(got.UnmarshalBinary(encoded)).Should(BeNil())
+		Expect(got.GetX()).Should(Equal(p.GetX()))
+		Expect(got.GetRank()).Should(Equal(p.GetRank()))
+		Expect(got.GetCurve()).Should(Equal(p.GetCurve()))
+	})
+
+	It("round-trips BkParameters through Marshal/Unmarshal", func() {
+		ps := newSample()
+		encoded, err := ps.Marshal()
+		Expect(err).Should(BeNil())
+
+		var got BkParameters
+		Expect(got.Unmarshal(encoded)).Should(BeNil())
+		Expect(got).Should(Equal(ps))
+	})
+
+	It("round-trips BkParameters through the compressed wire format", func() {
+		ps := newSample()
+		encoded, err := ps.MarshalCompressed()
+		Expect(err).Should(BeNil())
+
+		var got BkParameters
+		Expect(got.UnmarshalCompressed(encoded)).Should(BeNil())
+		Expect(got).Should(Equal(ps))
+	})
+
+	It("rejects a mixed-curve set for the compressed wire format", func() {
+		ps := BkParameters{
+			NewBkParameterWithCurve(big.NewInt(1), 0, CurveBLS12381G1),
+			NewBkParameterWithCurve(big.NewInt(2), 1, CurveSECP256K1),
+		}
+		_, err := ps.MarshalCompressed()
+		Expect(err).Should(Equal(ErrInvalidWireFormat))
+	})
+
+	It("Unmarshal skips an unrecognized trailing field instead of rejecting the message", func() {
+		p := NewBkParameterWithCurve(big.NewInt(12345), 3, CurveBLS12381G2)
+		encoded, err := p.Marshal()
+		Expect(err).Should(BeNil())
+
+		// Append a field 4 a newer writer might add to BkParameterMessage:
+		// tag (field 4, varint) followed by a varint value.
+		encoded = appendProtoTag(encoded, 4, protoWireVarint)
+		encoded = appendProtoVarint(encoded, 99)
+
+		got := &BkParameter{}
+		Expect(got.Unmarshal(encoded)).Should(BeNil())
+		Expect(got.GetX()).Should(Equal(p.GetX()))
+		Expect(got.GetRank()).Should(Equal(p.GetRank()))
+		Expect(got.GetCurve()).Should(Equal(p.GetCurve()))
+	})
+
+	It("BkParameters.Unmarshal skips an unrecognized top-level field", func() {
+		ps := newSample()
+		encoded, err := ps.Marshal()
+		Expect(err).Should(BeNil())
+
+		// Append a field 2 a newer writer might add to BkParametersMessage:
+		// a length-delimited field this reader doesn't know about.
+		encoded = appendProtoTag(encoded, 2, protoWireBytes)
+		encoded = appendProtoVarint(encoded, 3)
+		encoded = append(encoded, []byte("abc")...)
+
+		var got BkParameters
+		Expect(got.Unmarshal(encoded)).Should(BeNil())
+		Expect(got).Should(Equal(ps))
+	})
+
+	It("round-trips BkParameters through JSON", func() {
+		ps := newSample()
+		encoded, err := ps.MarshalJSON()
+		Expect(err).Should(BeNil())
+
+		var got BkParameters
+		Expect(got.UnmarshalJSON(encoded)).Should(BeNil())
+		Expect(got).Should(Equal(ps))
+	})
+})
@@ -0,0 +1,156 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package matrix implements matrices over Z/fieldOrderZ with the handful of
+// operations (construction, indexing and inversion) that the interpolation
+// packages need to turn a system of linear equations into coefficients.
+package matrix
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/getamis/alice/crypto/utils"
+)
+
+var (
+	// ErrNotInvertableMatrix is returned when a matrix has no inverse over
+	// its field order (e.g. it's singular, or not square).
+	ErrNotInvertableMatrix = errors.New("not invertable matrix")
+	// ErrInvalidMatrix is returned when the rows supplied to NewMatrix are
+	// empty or of inconsistent length.
+	ErrInvalidMatrix = errors.New("invalid matrix")
+)
+
+// Matrix is a 2-dimensional matrix whose entries are reduced modulo
+// fieldOrder.
+type Matrix struct {
+	fieldOrder *big.Int
+	matrix     [][]*big.Int
+}
+
+// NewMatrix builds a Matrix from m, reducing every entry modulo fieldOrder.
+// All rows of m must have the same, non-zero length.
+func NewMatrix(fieldOrder *big.Int, m [][]*big.Int) (*Matrix, error) {
+	if err := utils.EnsureFieldOrder(fieldOrder); err != nil {
+		return nil, err
+	}
+	if len(m) == 0 || len(m[0]) == 0 {
+		return nil, ErrInvalidMatrix
+	}
+	numberColumn := len(m[0])
+	result := make([][]*big.Int, len(m))
+	for i, row := range m {
+		if len(row) != numberColumn {
+			return nil, ErrInvalidMatrix
+		}
+		result[i] = make([]*big.Int, numberColumn)
+		for j, v := range row {
+			result[i][j] = utils.Mod(v, fieldOrder)
+		}
+	}
+	return &Matrix{
+		fieldOrder: new(big.Int).Set(fieldOrder),
+		matrix:     result,
+	}, nil
+}
+
+// GetNumberRow returns the number of rows.
+func (m *Matrix) GetNumberRow() int {
+	return len(m.matrix)
+}
+
+// GetNumberColumn returns the number of columns.
+func (m *Matrix) GetNumberColumn() int {
+	return len(m.matrix[0])
+}
+
+// Get returns the entry at row i, column j.
+func (m *Matrix) Get(i, j int) *big.Int {
+	return new(big.Int).Set(m.matrix[i][j])
+}
+
+// GetRow returns a copy of row i.
+func (m *Matrix) GetRow(i int) []*big.Int {
+	row := make([]*big.Int, len(m.matrix[i]))
+	for j, v := range m.matrix[i] {
+		row[j] = new(big.Int).Set(v)
+	}
+	return row
+}
+
+// Inverse computes the inverse of m over Z/fieldOrderZ using Gauss-Jordan
+// elimination. m must be square; ErrNotInvertableMatrix is returned if it is
+// not, or if it is singular modulo fieldOrder.
+func (m *Matrix) Inverse() (*Matrix, error) {
+	n := m.GetNumberRow()
+	if n != m.GetNumberColumn() {
+		return nil, ErrNotInvertableMatrix
+	}
+
+	// Build the augmented matrix [m | I].
+	aug := make([][]*big.Int, n)
+	for i := 0; i < n; i++ {
+		aug[i] = make([]*big.Int, 2*n)
+		for j := 0; j < n; j++ {
+			aug[i][j] = new(big.Int).Set(m.matrix[i][j])
+		}
+		for j := 0; j < n; j++ {
+			if i == j {
+				aug[i][n+j] = big.NewInt(1)
+			} else {
+				aug[i][n+j] = big.NewInt(0)
+			}
+		}
+	}
+
+	for col := 0; col < n; col++ {
+		pivotRow := -1
+		for row := col; row < n; row++ {
+			if aug[row][col].Sign() != 0 {
+				pivotRow = row
+				break
+			}
+		}
+		if pivotRow == -1 {
+			return nil, ErrNotInvertableMatrix
+		}
+		aug[col], aug[pivotRow] = aug[pivotRow], aug[col]
+
+		inv := new(big.Int).ModInverse(aug[col][col], m.fieldOrder)
+		if inv == nil {
+			return nil, ErrNotInvertableMatrix
+		}
+		for j := 0; j < 2*n; j++ {
+			aug[col][j] = utils.Mod(new(big.Int).Mul(aug[col][j], inv), m.fieldOrder)
+		}
+
+		for row := 0; row < n; row++ {
+			if row == col || aug[row][col].Sign() == 0 {
+				continue
+			}
+			factor := aug[row][col]
+			for j := 0; j < 2*n; j++ {
+				sub := new(big.Int).Mul(factor, aug[col][j])
+				aug[row][j] = utils.Mod(new(big.Int).Sub(aug[row][j], sub), m.fieldOrder)
+			}
+		}
+	}
+
+	inverse := make([][]*big.Int, n)
+	for i := 0; i < n; i++ {
+		inverse[i] = aug[i][n:]
+	}
+	return NewMatrix(m.fieldOrder, inverse)
+}
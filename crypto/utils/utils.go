@@ -0,0 +1,55 @@
+// Copyright © 2020 AMIS Technologies
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package utils provides small shared helpers (big.Int constants, field
+// sanity checks, ...) used across the crypto packages so they don't each
+// redefine the same boilerplate.
+package utils
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	// ErrLessOrEqualBig2 is returned when a value that is required to be a
+	// field order (and therefore > 2) is not.
+	ErrLessOrEqualBig2 = errors.New("less or equal big 2")
+)
+
+// Commonly used big.Int constants.
+var (
+	Big0 = big.NewInt(0)
+	Big1 = big.NewInt(1)
+	Big2 = big.NewInt(2)
+	Big3 = big.NewInt(3)
+)
+
+// EnsureFieldOrder makes sure fieldOrder is large enough to be usable as a
+// modulus for field arithmetic (i.e. strictly greater than 2).
+func EnsureFieldOrder(fieldOrder *big.Int) error {
+	if fieldOrder == nil || fieldOrder.Cmp(Big2) <= 0 {
+		return ErrLessOrEqualBig2
+	}
+	return nil
+}
+
+// Mod returns x mod m, normalized to the range [0, m).
+func Mod(x *big.Int, m *big.Int) *big.Int {
+	result := new(big.Int).Mod(x, m)
+	if result.Sign() < 0 {
+		result.Add(result, m)
+	}
+	return result
+}